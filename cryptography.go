@@ -1,16 +1,23 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"crypto"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 )
 
 /* Go Cryptography Examples
@@ -119,6 +126,544 @@ func decryptAsymmetric(ciphertext []byte, privateKey *rsa.PrivateKey) ([]byte, e
 	return plaintext, nil
 }
 
+// --- Hybrid Cryptography (RSA + AES-GCM) ---
+//
+// RSA-OAEP can only encrypt messages smaller than the modulus (minus padding
+// overhead), so it is unsuitable for anything but tiny payloads such as a
+// symmetric key. EncryptHybrid/DecryptHybrid implement the standard envelope
+// scheme: the payload is encrypted with a freshly generated AES-GCM key, and
+// only that key is wrapped with RSA-OAEP.
+
+const hybridEnvelopeVersion = 1
+
+// EncryptHybrid encrypts plaintext of arbitrary size for the holder of pub.
+// It generates a random 32-byte AES key, encrypts plaintext with AES-GCM,
+// wraps the AES key with RSA-OAEP/SHA-256, and returns a single
+// self-describing envelope:
+//
+//	2 bytes  version
+//	2 bytes  wrapped key length (big-endian)
+//	N bytes  RSA-OAEP wrapped AES key
+//	12 bytes GCM nonce
+//	rest     AES-GCM ciphertext (including the authentication tag)
+func EncryptHybrid(plaintext []byte, pub *rsa.PublicKey) ([]byte, error) {
+	aesKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, aesKey); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := encryptSymmetricWithKey(plaintext, aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, aesKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) > 0xFFFF {
+		return nil, fmt.Errorf("wrapped key too large for envelope: %d bytes", len(wrappedKey))
+	}
+
+	envelope := make([]byte, 0, 4+len(wrappedKey)+len(ciphertext))
+	envelope = binary.BigEndian.AppendUint16(envelope, hybridEnvelopeVersion)
+	envelope = binary.BigEndian.AppendUint16(envelope, uint16(len(wrappedKey)))
+	envelope = append(envelope, wrappedKey...)
+	envelope = append(envelope, ciphertext...)
+
+	return envelope, nil
+}
+
+// DecryptHybrid reverses EncryptHybrid: it unwraps the AES key with priv and
+// decrypts the remainder of the envelope with AES-GCM.
+func DecryptHybrid(envelope []byte, priv *rsa.PrivateKey) ([]byte, error) {
+	if len(envelope) < 4 {
+		return nil, fmt.Errorf("envelope too short")
+	}
+
+	version := binary.BigEndian.Uint16(envelope[0:2])
+	if version != hybridEnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version: %d", version)
+	}
+
+	keyLen := int(binary.BigEndian.Uint16(envelope[2:4]))
+	rest := envelope[4:]
+	if len(rest) < keyLen {
+		return nil, fmt.Errorf("envelope too short for wrapped key")
+	}
+	wrappedKey, ciphertext := rest[:keyLen], rest[keyLen:]
+
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, wrappedKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptSymmetric(ciphertext, aesKey)
+}
+
+// encryptSymmetricWithKey is the fixed-key counterpart of encryptSymmetric,
+// used internally by EncryptHybrid so the caller can supply the key to wrap
+// rather than receiving a freshly generated one.
+func encryptSymmetricWithKey(plaintext []byte, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// --- Streaming Cryptography ---
+//
+// encryptSymmetric/decryptSymmetric operate on an entire plaintext held in
+// memory, which is unworkable for large files such as archives or database
+// dumps. EncryptStream/DecryptStream process the data in fixed-size chunks
+// instead, each sealed independently with AES-GCM.
+
+const (
+	streamMagic            = 0x67637374 // "gcst"
+	streamVersion          = 1
+	streamChunkSize        = 64 * 1024
+	streamNoncePrefixLen   = 8
+	streamHeaderLen        = 4 + 1 + 4 + streamNoncePrefixLen
+	streamChunkNonceLen    = streamNoncePrefixLen + 4
+	streamChunkFrameHeader = 1 + 4 // final flag + ciphertext length
+)
+
+// EncryptStream reads plaintext from in, encrypts it in streamChunkSize
+// chunks using AES-GCM, and writes the result to out. The output begins
+// with a header (magic number, version, chunk size, random nonce prefix)
+// followed by a sequence of framed chunks, each prefixed with a final-chunk
+// flag and a length. The nonce for chunk i is the header's nonce prefix
+// concatenated with the big-endian chunk counter i; the counter and the
+// final-chunk flag are both authenticated as associated data, so truncating
+// the stream or reordering/dropping chunks causes DecryptStream to fail
+// rather than silently return a prefix of the plaintext.
+func EncryptStream(in io.Reader, out io.Writer, key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	noncePrefix := make([]byte, streamNoncePrefixLen)
+	if _, err := io.ReadFull(rand.Reader, noncePrefix); err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, streamHeaderLen)
+	header = binary.BigEndian.AppendUint32(header, streamMagic)
+	header = append(header, streamVersion)
+	header = binary.BigEndian.AppendUint32(header, streamChunkSize)
+	header = append(header, noncePrefix...)
+	if _, err := out.Write(header); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(in)
+	buf := make([]byte, streamChunkSize)
+	nonce := make([]byte, streamChunkNonceLen)
+	copy(nonce, noncePrefix)
+
+	for counter := uint32(0); ; counter++ {
+		n, readErr := io.ReadFull(br, buf)
+		if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+			return readErr
+		}
+
+		_, peekErr := br.Peek(1)
+		final := peekErr != nil
+
+		binary.BigEndian.PutUint32(nonce[streamNoncePrefixLen:], counter)
+		aad := chunkAAD(counter, final)
+		ciphertext := gcm.Seal(nil, nonce, buf[:n], aad)
+
+		frame := make([]byte, 0, streamChunkFrameHeader+len(ciphertext))
+		if final {
+			frame = append(frame, 1)
+		} else {
+			frame = append(frame, 0)
+		}
+		frame = binary.BigEndian.AppendUint32(frame, uint32(len(ciphertext)))
+		frame = append(frame, ciphertext...)
+		if _, err := out.Write(frame); err != nil {
+			return err
+		}
+
+		if final {
+			return nil
+		}
+	}
+}
+
+// DecryptStream reverses EncryptStream, verifying and decrypting each chunk
+// in turn and writing the recovered plaintext to out. It returns an error
+// if the stream ends before a chunk marked final has been verified, which
+// defeats truncation attacks that simply drop the tail of the ciphertext.
+func DecryptStream(in io.Reader, out io.Writer, key []byte) error {
+	header := make([]byte, streamHeaderLen)
+	if _, err := io.ReadFull(in, header); err != nil {
+		return fmt.Errorf("reading stream header: %w", err)
+	}
+	if magic := binary.BigEndian.Uint32(header[0:4]); magic != streamMagic {
+		return fmt.Errorf("not a valid encrypted stream (bad magic)")
+	}
+	if version := header[4]; version != streamVersion {
+		return fmt.Errorf("unsupported stream version: %d", version)
+	}
+	chunkSize := binary.BigEndian.Uint32(header[5:9])
+	noncePrefix := header[9:streamHeaderLen]
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, streamChunkNonceLen)
+	copy(nonce, noncePrefix)
+
+	frameHeader := make([]byte, streamChunkFrameHeader)
+	ciphertext := make([]byte, 0, chunkSize+uint32(gcm.Overhead()))
+
+	for counter := uint32(0); ; counter++ {
+		if _, err := io.ReadFull(in, frameHeader); err != nil {
+			return fmt.Errorf("stream truncated before final chunk: %w", err)
+		}
+		final := frameHeader[0] == 1
+		length := binary.BigEndian.Uint32(frameHeader[1:5])
+
+		if cap(ciphertext) < int(length) {
+			ciphertext = make([]byte, length)
+		} else {
+			ciphertext = ciphertext[:length]
+		}
+		if _, err := io.ReadFull(in, ciphertext); err != nil {
+			return fmt.Errorf("stream truncated mid-chunk: %w", err)
+		}
+
+		binary.BigEndian.PutUint32(nonce[streamNoncePrefixLen:], counter)
+		aad := chunkAAD(counter, final)
+		plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+		if err != nil {
+			return fmt.Errorf("chunk %d failed authentication: %w", counter, err)
+		}
+
+		if _, err := out.Write(plaintext); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
+
+// chunkAAD builds the associated data for a stream chunk: the big-endian
+// chunk counter followed by a byte marking whether this is the final chunk.
+// Binding both into the AAD means an attacker cannot reorder chunks or
+// relabel a non-final chunk as final without breaking GCM authentication.
+func chunkAAD(counter uint32, final bool) []byte {
+	aad := make([]byte, 5)
+	binary.BigEndian.PutUint32(aad[0:4], counter)
+	if final {
+		aad[4] = 1
+	}
+	return aad
+}
+
+// --- Key Persistence (PEM) ---
+//
+// The original demo marshals a public key only to print it and then
+// discards both keys. Anything that actually uses this package needs to
+// write keys to disk and load them back, so SavePrivateKeyPEM/
+// LoadPrivateKeyPEM and SavePublicKeyPEM/LoadPublicKeyPEM round-trip RSA
+// keys through standard PEM files.
+
+const (
+	// pemKDFIterations follows OWASP's PBKDF2-HMAC-SHA256 guidance (>= 600,000).
+	pemKDFIterations = 600000
+	pemKDFSaltLen    = 16
+	pemKDFName       = "PBKDF2-HMAC-SHA256"
+)
+
+// SavePublicKeyPEM writes pub to path as a PEM-encoded PKIX public key.
+func SavePublicKeyPEM(path string, pub *rsa.PublicKey) error {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	block := &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	return os.WriteFile(path, pem.EncodeToMemory(block), 0o644)
+}
+
+// LoadPublicKeyPEM reads a PEM-encoded PKIX public key from path.
+func LoadPublicKeyPEM(path string) (*rsa.PublicKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an RSA public key", path)
+	}
+	return rsaKey, nil
+}
+
+// SavePrivateKeyPEM writes priv to path as a PEM-encoded PKCS#8 private key.
+// If passphrase is non-nil, the key is encrypted: a 32-byte key is derived
+// from the passphrase with PBKDF2-HMAC-SHA256 (salt stored in the PEM
+// headers), and the PKCS#8 bytes are sealed with AES-GCM under that key. If
+// passphrase is nil, the key is written in the clear.
+func SavePrivateKeyPEM(path string, priv *rsa.PrivateKey, passphrase []byte) error {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+
+	if passphrase == nil {
+		block := &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+		return os.WriteFile(path, pem.EncodeToMemory(block), 0o600)
+	}
+
+	salt := make([]byte, pemKDFSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	key := pbkdf2HMACSHA256(passphrase, salt, pemKDFIterations, 32)
+
+	ciphertext, err := encryptSymmetricWithKey(der, key)
+	if err != nil {
+		return err
+	}
+
+	block := &pem.Block{
+		Type: "ENCRYPTED PRIVATE KEY",
+		Headers: map[string]string{
+			"KDF":        pemKDFName,
+			"Salt":       hex.EncodeToString(salt),
+			"Iterations": strconv.Itoa(pemKDFIterations),
+		},
+		Bytes: ciphertext,
+	}
+	return os.WriteFile(path, pem.EncodeToMemory(block), 0o600)
+}
+
+// LoadPrivateKeyPEM reads an RSA private key from path, accepting PKCS#1
+// ("RSA PRIVATE KEY"), PKCS#8 ("PRIVATE KEY"), and the encrypted
+// ("ENCRYPTED PRIVATE KEY") format written by SavePrivateKeyPEM. passphrase
+// must be supplied (and non-nil) to load an encrypted key; it is ignored
+// otherwise.
+func LoadPrivateKeyPEM(path string, passphrase []byte) (*rsa.PrivateKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in %s", path)
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s does not contain an RSA private key", path)
+		}
+		return rsaKey, nil
+
+	case "ENCRYPTED PRIVATE KEY":
+		if passphrase == nil {
+			return nil, fmt.Errorf("%s is encrypted: a passphrase is required", path)
+		}
+		salt, err := hex.DecodeString(block.Headers["Salt"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid salt header: %w", err)
+		}
+		iterations, err := strconv.Atoi(block.Headers["Iterations"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid iterations header: %w", err)
+		}
+
+		key := pbkdf2HMACSHA256(passphrase, salt, iterations, 32)
+		der, err := decryptSymmetric(block.Bytes, key)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting private key (wrong passphrase?): %w", err)
+		}
+		rsaKey, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, err
+		}
+		return rsaKey.(*rsa.PrivateKey), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q in %s", block.Type, path)
+	}
+}
+
+// pbkdf2HMACSHA256 derives a keyLen-byte key from password and salt using
+// PBKDF2 (RFC 8018) with HMAC-SHA256 as the pseudorandom function. The
+// standard library has no PBKDF2 implementation, so this is a small
+// self-contained one to avoid pulling in an external dependency for a
+// single KDF call.
+func pbkdf2HMACSHA256(password, salt []byte, iterations, keyLen int) []byte {
+	const hLen = sha256.Size
+
+	numBlocks := (keyLen + hLen - 1) / hLen
+	derived := make([]byte, 0, numBlocks*hLen)
+
+	mac := hmac.New(sha256.New, password)
+	for block := 1; block <= numBlocks; block++ {
+		mac.Reset()
+		mac.Write(salt)
+		blockIndex := make([]byte, 4)
+		binary.BigEndian.PutUint32(blockIndex, uint32(block))
+		mac.Write(blockIndex)
+
+		u := mac.Sum(nil)
+		t := make([]byte, hLen)
+		copy(t, u)
+
+		for i := 1; i < iterations; i++ {
+			mac.Reset()
+			mac.Write(u)
+			u = mac.Sum(nil)
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+
+		derived = append(derived, t...)
+	}
+
+	return derived[:keyLen]
+}
+
+// --- Digital Signatures (RSA-PSS) ---
+//
+// The OAEP functions above provide confidentiality but not authenticity.
+// SignMessage/VerifySignature add the other half of asymmetric crypto using
+// RSA-PSS with SHA-256, the signature scheme recommended alongside OAEP.
+
+// SignMessage signs msg with priv using RSA-PSS/SHA-256 and a salt length
+// equal to the hash size, and returns the signature.
+func SignMessage(msg []byte, priv *rsa.PrivateKey) ([]byte, error) {
+	digest := sha256.Sum256(msg)
+	return rsa.SignPSS(rand.Reader, priv, crypto.SHA256, digest[:], &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	})
+}
+
+// VerifySignature reports whether sig is a valid RSA-PSS/SHA-256 signature
+// of msg under pub. It returns nil on success and an error otherwise.
+func VerifySignature(msg, sig []byte, pub *rsa.PublicKey) error {
+	digest := sha256.Sum256(msg)
+	return rsa.VerifyPSS(pub, crypto.SHA256, digest[:], sig, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+}
+
+// SignStream is the streaming counterpart of SignMessage: it hashes r
+// incrementally so large files can be signed without being buffered in
+// memory.
+func SignStream(r io.Reader, priv *rsa.PrivateKey) ([]byte, error) {
+	digest, err := sha256Stream(r)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPSS(rand.Reader, priv, crypto.SHA256, digest, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       crypto.SHA256,
+	})
+}
+
+// VerifyStream is the streaming counterpart of VerifySignature.
+func VerifyStream(r io.Reader, sig []byte, pub *rsa.PublicKey) error {
+	digest, err := sha256Stream(r)
+	if err != nil {
+		return err
+	}
+	return rsa.VerifyPSS(pub, crypto.SHA256, digest, sig, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+}
+
+// sha256Stream computes the SHA-256 digest of r without buffering its
+// entire contents.
+func sha256Stream(r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// --- Legacy PKCS#1 v1.5 Session Key Unwrap ---
+//
+// Some systems still wrap session keys with PKCS#1 v1.5 instead of OAEP.
+// Naively decrypting such a ciphertext and branching on whether the padding
+// was valid opens a Bleichenbacher-style padding oracle: an attacker can
+// recover the plaintext by observing which ciphertexts are "rejected". The
+// stdlib's rsa.DecryptPKCS1v15SessionKey defends against this by copying
+// the recovered key into a caller-supplied buffer in constant time only
+// when the padding is valid, and silently leaving the buffer untouched
+// (i.e. still holding the random key the caller pre-filled it with) when
+// padding is invalid.
+//
+// DecryptSessionKeyPKCS1v15 wraps that routine: callers must not attempt to
+// distinguish "padding failed" from "padding succeeded" by inspecting the
+// returned error or comparing the output to anything — doing so reintroduces
+// the exact oracle this function exists to avoid. The only non-oracle
+// errors returned are structural (e.g. the ciphertext or requested key
+// length is invalid for this key), never a verdict on padding validity.
+func DecryptSessionKeyPKCS1v15(priv *rsa.PrivateKey, ciphertext []byte, keyLen int) ([]byte, error) {
+	fallbackKey := make([]byte, keyLen)
+	if _, err := io.ReadFull(rand.Reader, fallbackKey); err != nil {
+		return nil, err
+	}
+
+	if err := rsa.DecryptPKCS1v15SessionKey(rand.Reader, priv, ciphertext, fallbackKey); err != nil {
+		return nil, err
+	}
+
+	// fallbackKey now holds either the recovered session key (valid padding)
+	// or the original random bytes (invalid padding) — indistinguishably,
+	// by design.
+	return fallbackKey, nil
+}
+
 // --- Main Function to Demonstrate Usage ---
 
 func main() {
@@ -178,4 +723,106 @@ func main() {
 		return
 	}
 	fmt.Printf("Decrypted Asymmetric Plaintext: %s\n", asymmetricPlaintext)
+
+	fmt.Println("\n--- Hybrid Cryptography (RSA + AES-GCM) ---")
+	// Hybrid encryption lets us protect a message of any size with the
+	// public key, unlike plain OAEP which is limited to a few hundred bytes.
+	hybridEnvelope, err := EncryptHybrid([]byte(originalMessage), publicKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Hybrid encryption failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Hybrid Envelope (first 16 bytes): %x...\n", hybridEnvelope[:16])
+
+	hybridPlaintext, err := DecryptHybrid(hybridEnvelope, privateKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Hybrid decryption failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Decrypted Hybrid Plaintext: %s\n", hybridPlaintext)
+
+	fmt.Println("\n--- Streaming Cryptography ---")
+	// Streaming encryption avoids holding the whole payload in memory, so
+	// it scales to files far larger than the short string used above.
+	var streamCiphertext bytes.Buffer
+	if err := EncryptStream(bytes.NewReader(symmetricKey), &streamCiphertext, symmetricKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Stream encryption failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Encrypted Stream Size: %d bytes\n", streamCiphertext.Len())
+
+	var streamPlaintext bytes.Buffer
+	if err := DecryptStream(&streamCiphertext, &streamPlaintext, symmetricKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Stream decryption failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Decrypted Stream Matches Input: %t\n", bytes.Equal(streamPlaintext.Bytes(), symmetricKey))
+
+	fmt.Println("\n--- Key Persistence (PEM) ---")
+	// Persist the key pair generated above to a temporary directory and
+	// load it back, demonstrating both the plain and passphrase-encrypted
+	// private key formats.
+	keyDir, err := os.MkdirTemp("", "crypto-keys")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create temp dir for keys: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(keyDir)
+
+	pubKeyPath := keyDir + "/rsa_pub.pem"
+	if err := SavePublicKeyPEM(pubKeyPath, publicKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save public key: %v\n", err)
+		return
+	}
+	loadedPubKey, err := LoadPublicKeyPEM(pubKeyPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load public key: %v\n", err)
+		return
+	}
+	fmt.Printf("Public Key Round-Trip OK: %t\n", loadedPubKey.Equal(publicKey))
+
+	privKeyPath := keyDir + "/rsa_priv.pem"
+	passphrase := []byte("correct horse battery staple")
+	if err := SavePrivateKeyPEM(privKeyPath, privateKey, passphrase); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save encrypted private key: %v\n", err)
+		return
+	}
+	loadedPrivKey, err := LoadPrivateKeyPEM(privKeyPath, passphrase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load encrypted private key: %v\n", err)
+		return
+	}
+	fmt.Printf("Private Key Round-Trip OK: %t\n", loadedPrivKey.Equal(privateKey))
+
+	fmt.Println("\n--- Digital Signatures (RSA-PSS) ---")
+	signature, err := SignMessage([]byte(originalMessage), privateKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Signing failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Signature (first 16 bytes): %x...\n", signature[:16])
+
+	if err := VerifySignature([]byte(originalMessage), signature, publicKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Signature verification failed: %v\n", err)
+		return
+	}
+	fmt.Println("Signature Verified: true")
+
+	fmt.Println("\n--- Legacy PKCS#1 v1.5 Session Key Unwrap ---")
+	sessionKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, sessionKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate session key: %v\n", err)
+		return
+	}
+	wrappedSessionKey, err := rsa.EncryptPKCS1v15(rand.Reader, publicKey, sessionKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PKCS#1 v1.5 wrapping failed: %v\n", err)
+		return
+	}
+	unwrappedSessionKey, err := DecryptSessionKeyPKCS1v15(privateKey, wrappedSessionKey, len(sessionKey))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "PKCS#1 v1.5 unwrap failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Session Key Recovered: %t\n", bytes.Equal(unwrappedSessionKey, sessionKey))
 }
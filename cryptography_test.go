@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"testing"
+)
+
+// DecryptSessionKeyPKCS1v15 must behave identically, from the caller's
+// point of view, whether or not the PKCS#1 v1.5 padding was valid: the
+// only difference allowed is the content of the returned key, never the
+// error. These tests pin that property down so a future change can't
+// reintroduce a Bleichenbacher-style oracle.
+func TestDecryptSessionKeyPKCS1v15(t *testing.T) {
+	priv, pub, err := generateRSAKeys()
+	if err != nil {
+		t.Fatalf("generateRSAKeys: %v", err)
+	}
+
+	const keyLen = 32
+	sessionKey := make([]byte, keyLen)
+	if _, err := io.ReadFull(rand.Reader, sessionKey); err != nil {
+		t.Fatalf("generating session key: %v", err)
+	}
+
+	ciphertext, err := rsa.EncryptPKCS1v15(rand.Reader, pub, sessionKey)
+	if err != nil {
+		t.Fatalf("rsa.EncryptPKCS1v15: %v", err)
+	}
+
+	t.Run("well-formed ciphertext recovers the real session key", func(t *testing.T) {
+		got, err := DecryptSessionKeyPKCS1v15(priv, ciphertext, keyLen)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(got, sessionKey) {
+			t.Fatalf("recovered key does not match the wrapped session key")
+		}
+	})
+
+	t.Run("malformed ciphertext returns the random fallback, not an error", func(t *testing.T) {
+		malformed := make([]byte, len(ciphertext))
+		if _, err := io.ReadFull(rand.Reader, malformed); err != nil {
+			t.Fatalf("generating malformed ciphertext: %v", err)
+		}
+		// Keep the ciphertext below the modulus so it is structurally valid
+		// and only its padding is bad.
+		malformed[0] = 0
+
+		got, err := DecryptSessionKeyPKCS1v15(priv, malformed, keyLen)
+		if err != nil {
+			t.Fatalf("invalid padding must not surface as an error, got: %v", err)
+		}
+		if len(got) != keyLen {
+			t.Fatalf("fallback key has wrong length: got %d, want %d", len(got), keyLen)
+		}
+		if bytes.Equal(got, sessionKey) {
+			t.Fatalf("fallback key unexpectedly matches the real session key")
+		}
+	})
+
+	t.Run("valid and invalid padding are only distinguishable by output content", func(t *testing.T) {
+		malformed := make([]byte, len(ciphertext))
+		if _, err := io.ReadFull(rand.Reader, malformed); err != nil {
+			t.Fatalf("generating malformed ciphertext: %v", err)
+		}
+		malformed[0] = 0
+
+		_, validErr := DecryptSessionKeyPKCS1v15(priv, ciphertext, keyLen)
+		_, invalidErr := DecryptSessionKeyPKCS1v15(priv, malformed, keyLen)
+
+		if validErr != invalidErr {
+			t.Fatalf("error value leaks padding validity: valid=%v invalid=%v", validErr, invalidErr)
+		}
+	})
+}